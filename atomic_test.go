@@ -0,0 +1,55 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAtomicBool(t *testing.T) {
+	var ab atomicBool
+	if ab.Load() {
+		t.Fatal("expected zero value to be false")
+	}
+
+	ab.Store(true)
+	if !ab.Load() {
+		t.Fatal("expected Load to return true after Store(true)")
+	}
+
+	ab.Store(false)
+	if ab.Load() {
+		t.Fatal("expected Load to return false after Store(false)")
+	}
+
+	if !ab.TrySet(true) {
+		t.Fatal("expected TrySet(true) to succeed from false")
+	}
+	if ab.TrySet(true) {
+		t.Fatal("expected TrySet(true) to fail when already true")
+	}
+	if !ab.TrySet(false) {
+		t.Fatal("expected TrySet(false) to succeed from true")
+	}
+	if ab.TrySet(false) {
+		t.Fatal("expected TrySet(false) to fail when already false")
+	}
+}
+
+func TestAtomicError(t *testing.T) {
+	var ae atomicError
+	if ae.Value() != nil {
+		t.Fatal("expected zero value to be nil")
+	}
+
+	err := errors.New("boom")
+	ae.Set(err)
+	if ae.Value() != err {
+		t.Fatalf("got %v, want %v", ae.Value(), err)
+	}
+}
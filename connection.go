@@ -0,0 +1,288 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"strconv"
+	"time"
+)
+
+// mysqlConn represents a connection to a MySQL server.
+type mysqlConn struct {
+	buf              buffer
+	netConn          net.Conn
+	affectedRows     uint64
+	insertId         uint64
+	cfg              *Config
+	maxAllowedPacket int
+	maxWriteSize     int
+	writeTimeout     time.Duration
+	flags            clientFlag
+	status           statusFlag
+	sequence         uint8
+	parseTime        bool
+	strict           bool
+	connectionID     uint32 // set from the handshake initialization packet; used by KILL QUERY
+
+	authPlugin authPlugin
+
+	// for context support (From Go 1.8)
+	watching bool
+	watcher  chan<- context.Context
+	closech  chan struct{}
+	finished chan<- struct{}
+	canceled atomicError // set non-nil if conn is canceled
+	closed   atomicBool  // set when conn is closed, before closech is closed
+}
+
+// Close implements driver.Conn.
+func (mc *mysqlConn) Close() (err error) {
+	if !mc.closed.Load() {
+		err = mc.writeCommandPacket(comQuit)
+	}
+	mc.cleanup()
+	return
+}
+
+// cleanup releases mc's resources exactly once, regardless of how Close was
+// reached (normal close, handshake failure, or cancellation).
+func (mc *mysqlConn) cleanup() {
+	if !mc.closed.TrySet(true) {
+		return
+	}
+	if mc.netConn != nil {
+		if err := mc.netConn.Close(); err != nil {
+			errLog.Print(err)
+		}
+	}
+	close(mc.closech)
+}
+
+func (mc *mysqlConn) error() error {
+	if mc.closed.Load() {
+		if err := mc.canceled.Value(); err != nil {
+			return err
+		}
+		return ErrInvalidConn
+	}
+	return nil
+}
+
+// Begin implements driver.Conn.
+func (mc *mysqlConn) Begin() (driver.Tx, error) {
+	return mc.begin(false)
+}
+
+func (mc *mysqlConn) begin(readOnly bool) (driver.Tx, error) {
+	if mc.closed.Load() {
+		return nil, ErrInvalidConn
+	}
+	var q string
+	if readOnly {
+		q = "START TRANSACTION READ ONLY"
+	} else {
+		q = "START TRANSACTION"
+	}
+	if _, err := mc.exec(q); err != nil {
+		return nil, mc.markBadConn(err)
+	}
+	return &mysqlTx{mc}, nil
+}
+
+// markBadConn returns the error recorded by cancel in place of err once mc
+// has been cancelled (unless err is ErrInvalidConn), and err unchanged
+// otherwise. It does not flag mc for discard by database/sql.
+func (mc *mysqlConn) markBadConn(err error) error {
+	if mc == nil {
+		return err
+	}
+	if err != ErrInvalidConn && mc.canceled.Value() != nil {
+		return mc.canceled.Value()
+	}
+	return err
+}
+
+// startWatcher starts the watcher goroutine that waits on mc.watcher for a
+// context to babysit and cancels mc (see cancel) when that context is done.
+// There is exactly one watcher per connection, started once in
+// MySQLDriver.Open/connector.Connect, so in-flight-query cancellation never
+// leaks a goroutine per query.
+func (mc *mysqlConn) startWatcher() {
+	watching := make(chan context.Context, 1)
+	mc.watcher = watching
+	finished := make(chan struct{})
+	mc.finished = finished
+	go func() {
+		for {
+			var ctx context.Context
+			select {
+			case ctx = <-watching:
+			case <-mc.closech:
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				mc.cancel(ctx.Err())
+			case <-finished:
+			case <-mc.closech:
+				return
+			}
+		}
+	}()
+}
+
+// watchCancel arranges for mc to be cancelled (see cancel) when ctx is done.
+// It must be paired with a call to finish once the operation it guards has
+// completed.
+func (mc *mysqlConn) watchCancel(ctx context.Context) error {
+	if mc.watching {
+		// Reach here if canceled, a watcher is already running.
+		mc.cleanup()
+		return nil
+	}
+	if ctx.Done() == nil {
+		return nil
+	}
+	if mc.closed.Load() {
+		return ErrInvalidConn
+	}
+	mc.watching = true
+	mc.watcher <- ctx
+	return nil
+}
+
+// finish signals the watcher that the operation it was guarding has
+// completed normally, so it stops watching ctx.
+func (mc *mysqlConn) finish() {
+	if !mc.watching {
+		return
+	}
+	select {
+	case mc.finished <- struct{}{}:
+		mc.watching = false
+	case <-mc.closech:
+	}
+}
+
+// cancel aborts whatever ctx-guarded operation is currently in flight on mc.
+// Before the handshake has finished, mc.connectionID is still zero and the
+// server has nothing to KILL, so the only way to unblock the local
+// read/write is to close the connection directly; cleanup() is idempotent,
+// so this races harmlessly with a concurrent normal Close. Once the
+// connection ID is known, a blocked query is aborted via KILL QUERY on a
+// sibling connection instead, since MySQL provides no in-band way to abort a
+// running query on the connection that issued it. Either way, err is
+// recorded so markBadConn reports it; in the KILL QUERY case mc stays open,
+// and neither case returns driver.ErrBadConn, so database/sql does not
+// discard mc on that account.
+func (mc *mysqlConn) cancel(err error) {
+	mc.canceled.Set(err)
+	if mc.connectionID == 0 {
+		mc.cleanup()
+		return
+	}
+	mc.killQuery()
+}
+
+// killQuery opens a short-lived sibling connection (using mc's Config) and
+// issues KILL QUERY against mc.connectionID. Errors are deliberately
+// swallowed: if this fails, the worst case is that the original query keeps
+// running on the server until it finishes or the connection is closed.
+func (mc *mysqlConn) killQuery() {
+	killCfg := mc.cfg.Clone()
+	killCfg.Timeout = 3 * time.Second
+
+	killConn, err := (&connector{cfg: killCfg}).Connect(context.Background())
+	if err != nil {
+		return
+	}
+	defer killConn.Close()
+
+	sibling := killConn.(*mysqlConn)
+	_, _ = sibling.exec("KILL QUERY " + strconv.FormatUint(uint64(mc.connectionID), 10))
+}
+
+// QueryContext implements driver.QueryerContext.
+func (mc *mysqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	dargs, err := namedValueToValue(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mc.watchCancel(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.finish()
+
+	rows, err := mc.query(query, dargs)
+	if err != nil {
+		return nil, mc.markBadConn(err)
+	}
+	return rows, nil
+}
+
+// ExecContext implements driver.ExecerContext.
+func (mc *mysqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	dargs, err := namedValueToValue(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mc.watchCancel(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.finish()
+
+	res, err := mc.exec(query, dargs...)
+	if err != nil {
+		return nil, mc.markBadConn(err)
+	}
+	return res, nil
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (mc *mysqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := mc.watchCancel(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.finish()
+
+	if level := sql.IsolationLevel(opts.Isolation); level != sql.LevelDefault {
+		levelSQL, err := isolationLevelSQL(level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := mc.exec("SET TRANSACTION ISOLATION LEVEL " + levelSQL); err != nil {
+			return nil, mc.markBadConn(err)
+		}
+	}
+
+	tx, err := mc.begin(opts.ReadOnly)
+	if err != nil {
+		return nil, mc.markBadConn(err)
+	}
+	return tx, nil
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (mc *mysqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := mc.watchCancel(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.finish()
+
+	stmt, err := mc.Prepare(query)
+	if err != nil {
+		return nil, mc.markBadConn(err)
+	}
+	return stmt, nil
+}
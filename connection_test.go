@@ -0,0 +1,35 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCancelBeforeConnectionIDClosesDirectly verifies that cancelling mc
+// before the handshake has produced a connectionID closes the connection
+// directly instead of attempting a KILL QUERY, which would have nothing to
+// kill and would leave the blocked handshake read/write hanging (see
+// chunk0-2 review fix).
+func TestCancelBeforeConnectionIDClosesDirectly(t *testing.T) {
+	mc := &mysqlConn{closech: make(chan struct{})}
+
+	mc.cancel(errors.New("context canceled"))
+
+	if !mc.closed.Load() {
+		t.Fatal("expected cancel() to close mc when connectionID is unknown")
+	}
+	select {
+	case <-mc.closech:
+	default:
+		t.Fatal("expected closech to be closed")
+	}
+	if mc.canceled.Value() == nil {
+		t.Fatal("expected canceled error to be recorded")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+// maxPacketSize is the maximum size, in bytes, of a single MySQL protocol
+// packet payload (2^24 - 1, the largest value the 3-byte packet length
+// header can hold).
+const maxPacketSize = 1<<24 - 1
+
+// clientFlag is a bitset of capabilities the client advertises to the server
+// in the handshake response packet.
+type clientFlag uint32
+
+const (
+	clientLongPassword clientFlag = 1 << iota
+	clientFoundRows
+	clientLongFlag
+	clientConnectWithDB
+	clientNoSchema
+	clientCompress
+	clientODBC
+	clientLocalFiles
+	clientIgnoreSpace
+	clientProtocol41
+	clientInteractive
+	clientSSL
+	clientIgnoreSIGPIPE
+	clientTransactions
+	clientReserved
+	clientSecureConnection
+	clientMultiStatements
+	clientMultiResults
+	clientPSMultiResults
+	clientPluginAuth
+)
+
+// baseClientFlags are the capability flags this driver always advertises,
+// regardless of DSN params.
+const baseClientFlags = clientProtocol41 |
+	clientSecureConnection |
+	clientLongPassword |
+	clientTransactions |
+	clientPluginAuth
+
+// statusFlag is a bitset of server status flags returned in OK/EOF packets.
+type statusFlag uint16
+
+const (
+	statusInTrans statusFlag = 1 << iota
+	statusInAutocommit
+	statusReserved
+	statusMoreResultsExists
+	statusNoGoodIndexUsed
+	statusNoIndexUsed
+	statusCursorExists
+	statusLastRowSent
+	statusDBDropped
+	statusNoBackslashEscapes
+	statusMetadataChanged
+	statusQueryWasSlow
+	statusPSOutParams
+	statusInTransReadonly
+	statusSessionStateChanged
+)
+
+// clientCapabilityFlags returns the capability flags to advertise for cfg,
+// turning on CLIENT_MULTI_STATEMENTS/CLIENT_MULTI_RESULTS when the
+// corresponding DSN params are set so the server accepts multi-statement
+// requests and reports SERVER_MORE_RESULTS_EXISTS for multi-result-set
+// CALLs. CLIENT_MULTI_STATEMENTS implies the ability to return multiple
+// result sets, so it also turns on CLIENT_MULTI_RESULTS.
+func clientCapabilityFlags(cfg *Config) clientFlag {
+	flags := baseClientFlags
+	if cfg.MultiStatements {
+		flags |= clientMultiStatements
+	}
+	if cfg.MultiStatements || cfg.MultiResults {
+		flags |= clientMultiResults
+	}
+	return flags
+}
@@ -0,0 +1,28 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+)
+
+var errNamedValueNotSupported = errors.New("mysql: driver does not support the use of Named Parameters")
+
+// namedValueToValue converts driver.NamedValue args coming from the
+// context-aware database/sql entry points back into positional
+// driver.Value args, for callers that don't care about parameter names.
+func namedValueToValue(named []driver.NamedValue) ([]driver.Value, error) {
+	dargs := make([]driver.Value, len(named))
+	for n, param := range named {
+		if len(param.Name) > 0 {
+			return nil, errNamedValueNotSupported
+		}
+		dargs[n] = param.Value
+	}
+	return dargs, nil
+}
@@ -17,9 +17,11 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"net"
+	"sync"
 )
 
 // MySQLDriver is exported to make the driver directly accessible.
@@ -28,140 +30,67 @@ type MySQLDriver struct{}
 
 // DialFunc is a function which can be used to establish the network connection.
 // Custom dial functions must be registered with RegisterDial
+//
+// Deprecated: users should register a DialContextFunc with RegisterDialContext
 type DialFunc func(addr string) (net.Conn, error)
 
-var dials map[string]DialFunc
+// DialContextFunc is a function which can be used to establish the network
+// connection. Custom dial functions must be registered with
+// RegisterDialContext
+type DialContextFunc func(ctx context.Context, addr string) (net.Conn, error)
+
+var (
+	dialsLock sync.RWMutex
+	dials     map[string]DialContextFunc
+)
 
 // RegisterDial registers a custom dial function. It can then be used by the
 // network address mynet(addr), where mynet is the registered new network.
 // addr is passed as a parameter to the dial function.
-func RegisterDial(net string, dial DialFunc) {
+//
+// Deprecated: use RegisterDialContext instead
+func RegisterDial(network string, dial DialFunc) {
+	RegisterDialContext(network, func(_ context.Context, addr string) (net.Conn, error) {
+		return dial(addr)
+	})
+}
+
+// RegisterDialContext registers a custom dial function. It can then be used
+// by the network address mynet(addr), where mynet is the registered new
+// network. addr is passed as a parameter to the dial function, and a context
+// is passed in so that it can be cancelled while dialing.
+func RegisterDialContext(network string, dial DialContextFunc) {
+	dialsLock.Lock()
+	defer dialsLock.Unlock()
 	if dials == nil {
-		dials = make(map[string]DialFunc)
+		dials = make(map[string]DialContextFunc)
 	}
-	dials[net] = dial
+	dials[network] = dial
 }
 
 // Open new Connection.
 // See https://github.com/go-sql-driver/mysql#dsn-data-source-name for how
 // the DSN string is formated
 func (d MySQLDriver) Open(dsn string) (driver.Conn, error) {
-	var err error
-
-	// New mysqlConn
-	mc := &mysqlConn{
-		maxAllowedPacket: maxPacketSize,
-		maxWriteSize:     maxPacketSize - 1,
-	}
-	mc.cfg, err = ParseDSN(dsn)
-	if err != nil {
-		return nil, err
-	}
-	mc.parseTime = mc.cfg.ParseTime
-	mc.strict = mc.cfg.Strict
-
-	// Connect to Server
-	if dial, ok := dials[mc.cfg.Net]; ok {
-		mc.netConn, err = dial(mc.cfg.Addr)
-	} else {
-		nd := net.Dialer{Timeout: mc.cfg.Timeout}
-		mc.netConn, err = nd.Dial(mc.cfg.Net, mc.cfg.Addr)
-	}
+	cfg, err := ParseDSN(dsn)
 	if err != nil {
 		return nil, err
 	}
+	c := &connector{cfg: cfg}
+	return c.Connect(context.Background())
+}
 
-	// Enable TCP Keepalives on TCP connections
-	if tc, ok := mc.netConn.(*net.TCPConn); ok {
-		if err := tc.SetKeepAlive(true); err != nil {
-			// Don't send COM_QUIT before handshake.
-			mc.netConn.Close()
-			mc.netConn = nil
-			return nil, err
-		}
-	}
-
-	mc.buf = newBuffer(mc.netConn)
-
-	// Set I/O timeouts
-	mc.buf.timeout = mc.cfg.ReadTimeout
-	mc.writeTimeout = mc.cfg.WriteTimeout
-
-	// Reading Handshake Initialization Packet
-	authPluginName, authData, err := mc.readInitPacket()
-	if err != nil {
-		mc.cleanup()
-		return nil, err
-	}
-
-	// save the old auth data in case the server
-	// needs to use the old password scheme.
-	oldCipher := make([]byte, len(authData))
-	copy(oldCipher, authData)
-
-	// Handle pluggable authentication
-	if authPluginName == "" {
-		// assume that without a name, we are using
-		// the default.
-		authPluginName = defaultAuthPluginName
-	}
-
-	if apf, ok := authPluginFactories[authPluginName]; ok {
-		mc.authPlugin = apf(mc.cfg)
-		authData, err = mc.authPlugin.Next(authData)
-		if err != nil {
-			mc.cleanup()
-			return nil, err
-		}
-	} else {
-		// we'll tell the server in response that we are switching to our
-		// default plugin because we didn't recognize the one they sent us.
-		authPluginName = defaultAuthPluginName
-		mc.authPlugin = authPluginFactories[authPluginName](mc.cfg)
-
-		// zero-out the authData because the current authData was for
-		// a plugin we don't know about.
-		authData = make([]byte, 0)
-	}
-
-	// Send Client Authentication Packet
-	if err = mc.writeAuthPacket(authPluginName, authData); err != nil {
-		mc.cleanup()
-		return nil, err
-	}
-
-	// Handle response to auth packet, switch methods if possible
-	if err = handleAuthResult(mc, oldCipher); err != nil {
-		// Authentication failed and MySQL has already closed the connection
-		// (https://dev.mysql.com/doc/internals/en/authentication-fails.html).
-		// Do not send COM_QUIT, just cleanup and return the error.
-		mc.cleanup()
-		return nil, err
-	}
-
-	if mc.cfg.MaxAllowedPacket > 0 {
-		mc.maxAllowedPacket = mc.cfg.MaxAllowedPacket
-	} else {
-		// Get max allowed packet size
-		maxap, err := mc.getSystemVar("max_allowed_packet")
-		if err != nil {
-			mc.Close()
-			return nil, err
-		}
-		mc.maxAllowedPacket = stringToInt(maxap) - 1
-	}
-	if mc.maxAllowedPacket < maxPacketSize {
-		mc.maxWriteSize = mc.maxAllowedPacket
-	}
-
-	// Handle DSN Params
-	err = mc.handleParams()
+// OpenConnector implements driver.DriverContext.
+//
+// OpenConnector parses the DSN once and returns a driver.Connector that can
+// be handed to sql.OpenDB, avoiding the cost of re-parsing and re-validating
+// the DSN on every new connection.
+func (d MySQLDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := ParseDSN(dsn)
 	if err != nil {
-		mc.Close()
 		return nil, err
 	}
-
-	return mc, nil
+	return &connector{cfg: cfg}, nil
 }
 
 func init() {
@@ -0,0 +1,157 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidDSNNoSlash = errors.New("invalid DSN: missing the slash separating the database name")
+	errInvalidDSNAddr    = errors.New("invalid DSN: network address not terminated (missing closing brace)")
+)
+
+// Config is a configuration parsed from a DSN string by ParseDSN.
+// Use (*connector).Connect or NewConnector to dial with it.
+type Config struct {
+	User   string
+	Passwd string
+	Net    string
+	Addr   string
+	DBName string
+	Params map[string]string // other DSN params not recognized by ParseDSN
+
+	Timeout      time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	MaxAllowedPacket int
+
+	ParseTime bool
+	Strict    bool
+
+	// MultiStatements allows multiple semicolon-separated statements in a
+	// single Query/Exec call.
+	MultiStatements bool
+	// MultiResults allows stored procedures (CALL ...) to return more than
+	// one result set, iterated with Rows.NextResultSet. MultiStatements
+	// implies MultiResults.
+	MultiResults bool
+}
+
+// Clone returns a deep copy of cfg.
+func (cfg *Config) Clone() *Config {
+	c := *cfg
+	if cfg.Params != nil {
+		c.Params = make(map[string]string, len(cfg.Params))
+		for k, v := range cfg.Params {
+			c.Params[k] = v
+		}
+	}
+	return &c
+}
+
+// ParseDSN parses the DSN string to a Config.
+//
+// The DSN format is:
+//
+//	[username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
+func ParseDSN(dsn string) (*Config, error) {
+	cfg := &Config{Net: "tcp"}
+
+	rest := dsn
+	if idx := strings.IndexByte(rest, '?'); idx >= 0 {
+		if err := parseDSNParams(cfg, rest[idx+1:]); err != nil {
+			return nil, err
+		}
+		rest = rest[:idx]
+	}
+
+	slash := strings.LastIndexByte(rest, '/')
+	if slash < 0 {
+		return nil, errInvalidDSNNoSlash
+	}
+	cfg.DBName = rest[slash+1:]
+	rest = rest[:slash]
+
+	if at := strings.LastIndexByte(rest, '@'); at >= 0 {
+		userinfo := rest[:at]
+		rest = rest[at+1:]
+		if colon := strings.IndexByte(userinfo, ':'); colon >= 0 {
+			cfg.User = userinfo[:colon]
+			cfg.Passwd = userinfo[colon+1:]
+		} else {
+			cfg.User = userinfo
+		}
+	}
+
+	if rest != "" {
+		if paren := strings.IndexByte(rest, '('); paren >= 0 {
+			if rest[len(rest)-1] != ')' {
+				return nil, errInvalidDSNAddr
+			}
+			cfg.Net = rest[:paren]
+			cfg.Addr = rest[paren+1 : len(rest)-1]
+		} else {
+			cfg.Net = rest
+		}
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:3306"
+	}
+
+	return cfg, nil
+}
+
+// parseDSNParams parses the DSN "query string" (the part after '?') into
+// cfg, recognizing the params the driver understands and stashing the rest
+// in cfg.Params.
+func parseDSNParams(cfg *Config, params string) (err error) {
+	for _, v := range strings.Split(params, "&") {
+		key, value, found := strings.Cut(v, "=")
+		if !found {
+			continue
+		}
+
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "timeout":
+			cfg.Timeout, err = time.ParseDuration(value)
+		case "readTimeout":
+			cfg.ReadTimeout, err = time.ParseDuration(value)
+		case "writeTimeout":
+			cfg.WriteTimeout, err = time.ParseDuration(value)
+		case "maxAllowedPacket":
+			cfg.MaxAllowedPacket, err = strconv.Atoi(value)
+		case "parseTime":
+			cfg.ParseTime, err = strconv.ParseBool(value)
+		case "strict":
+			cfg.Strict, err = strconv.ParseBool(value)
+		case "multiStatements":
+			cfg.MultiStatements, err = strconv.ParseBool(value)
+		case "multiResults":
+			cfg.MultiResults, err = strconv.ParseBool(value)
+		default:
+			if cfg.Params == nil {
+				cfg.Params = make(map[string]string)
+			}
+			cfg.Params[key] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import "testing"
+
+func TestParseDSNMultiResultParams(t *testing.T) {
+	tests := []struct {
+		name                string
+		dsn                 string
+		wantMultiStatements bool
+		wantMultiResults    bool
+	}{
+		{"neither set", "user:pass@tcp(127.0.0.1:3306)/dbname", false, false},
+		{"multiStatements=true", "user:pass@/dbname?multiStatements=true", true, false},
+		{"multiResults=true", "user:pass@/dbname?multiResults=true", false, true},
+		{"both set alongside other params", "user:pass@/dbname?charset=utf8mb4&multiStatements=true&multiResults=true&parseTime=true", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseDSN(tt.dsn)
+			if err != nil {
+				t.Fatalf("ParseDSN(%q) returned error: %v", tt.dsn, err)
+			}
+			if cfg.MultiStatements != tt.wantMultiStatements {
+				t.Errorf("MultiStatements = %v, want %v", cfg.MultiStatements, tt.wantMultiStatements)
+			}
+			if cfg.MultiResults != tt.wantMultiResults {
+				t.Errorf("MultiResults = %v, want %v", cfg.MultiResults, tt.wantMultiResults)
+			}
+		})
+	}
+}
+
+func TestClientCapabilityFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		multiStatements bool
+		multiResults    bool
+		wantStatements  bool
+		wantResults     bool
+	}{
+		{"neither", false, false, false, false},
+		{"multiResults only", false, true, false, true},
+		{"multiStatements implies multiResults", true, false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{MultiStatements: tt.multiStatements, MultiResults: tt.multiResults}
+			flags := clientCapabilityFlags(cfg)
+
+			if got := flags&clientMultiStatements != 0; got != tt.wantStatements {
+				t.Errorf("clientMultiStatements = %v, want %v", got, tt.wantStatements)
+			}
+			if got := flags&clientMultiResults != 0; got != tt.wantResults {
+				t.Errorf("clientMultiResults = %v, want %v", got, tt.wantResults)
+			}
+			if flags&baseClientFlags != baseClientFlags {
+				t.Errorf("expected baseClientFlags to always be set, got %b", flags)
+			}
+		})
+	}
+}
+
+func TestConfigClone(t *testing.T) {
+	cfg := &Config{
+		User:   "u",
+		Params: map[string]string{"charset": "utf8mb4"},
+	}
+	clone := cfg.Clone()
+
+	clone.User = "changed"
+	clone.Params["charset"] = "latin1"
+
+	if cfg.User != "u" {
+		t.Errorf("mutating clone.User affected the original: %v", cfg.User)
+	}
+	if cfg.Params["charset"] != "utf8mb4" {
+		t.Errorf("mutating clone.Params affected the original: %v", cfg.Params["charset"])
+	}
+}
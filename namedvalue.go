@@ -0,0 +1,115 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// CheckNamedValue implements driver.NamedValueChecker.
+func (mc *mysqlConn) CheckNamedValue(nv *driver.NamedValue) (err error) {
+	nv.Value, err = converter{}.ConvertValue(nv.Value)
+	return
+}
+
+// CheckNamedValue implements driver.NamedValueChecker, resolving :name
+// placeholders captured at Prepare time (see rewriteNamedParams) against
+// each sql.Named value's Name.
+func (stmt *mysqlStmt) CheckNamedValue(nv *driver.NamedValue) (err error) {
+	if nv.Name != "" {
+		idx, ok := stmt.paramNames[nv.Name]
+		if !ok {
+			return fmt.Errorf("mysql: named parameter %q not found in query", nv.Name)
+		}
+		nv.Ordinal = idx + 1
+	}
+	nv.Value, err = converter{}.ConvertValue(nv.Value)
+	return
+}
+
+// rewriteNamedParams scans query for :name placeholders outside of quoted
+// strings, rewriting them to positional "?" parameters understood by the
+// MySQL binary protocol. It returns the rewritten query and an ordered
+// name->index map recording the position (0-based, among *all* "?" marks in
+// the rewritten query, not just the named ones) that each named placeholder
+// landed at, so mysqlStmt.CheckNamedValue can later bind sql.Named(name, v)
+// values to the right position. Plain "?" placeholders are left untouched.
+// An error is returned if the same name is used more than once, since each
+// bind value maps to exactly one physical "?" and reusing a name can't be
+// resolved to a single position.
+//
+// Bare "@name" (and "@@sysvar") is deliberately left untouched: it's also
+// MySQL's own syntax for user-defined session variables and system
+// variables (SET @x = 1, SELECT @rownum := @rownum + 1, CREATE USER
+// bob@localhost), so treating it as a bind placeholder would corrupt any
+// query using that idiom. Only the ":name" form is ever rewritten.
+func rewriteNamedParams(query string) (string, map[string]int, error) {
+	var out []byte
+	names := make(map[string]int)
+	argIndex := 0
+
+	quote := byte(0)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		if quote != 0 {
+			// Backslash escapes the following byte inside ' and " string
+			// literals (but not inside ` identifiers, which escape a quote
+			// only by doubling it), so it can't close the string or start a
+			// named placeholder.
+			if c == '\\' && quote != '`' && i+1 < len(query) {
+				out = append(out, c, query[i+1])
+				i++
+				continue
+			}
+			out = append(out, c)
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			out = append(out, c)
+		case c == '?':
+			out = append(out, c)
+			argIndex++
+		case c == ':':
+			j := i + 1
+			for j < len(query) && isNameByte(query[j]) {
+				j++
+			}
+			if j == i+1 {
+				// Lone ':' not followed by a name; not a placeholder, copy
+				// it through unchanged.
+				out = append(out, c)
+				continue
+			}
+			name := query[i+1 : j]
+			if _, ok := names[name]; ok {
+				return "", nil, fmt.Errorf("mysql: named parameter %q used more than once in query", name)
+			}
+			names[name] = argIndex
+			argIndex++
+			out = append(out, '?')
+			i = j - 1
+		default:
+			out = append(out, c)
+		}
+	}
+	if len(names) == 0 {
+		return query, nil, nil
+	}
+	return string(out), names, nil
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,110 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRewriteNamedParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantQuery string
+		wantNames map[string]int
+	}{
+		{
+			name:      "no placeholders",
+			query:     "SELECT 1",
+			wantQuery: "SELECT 1",
+			wantNames: nil,
+		},
+		{
+			name:      "only positional placeholders are untouched",
+			query:     "SELECT * FROM t WHERE a=? AND b=?",
+			wantQuery: "SELECT * FROM t WHERE a=? AND b=?",
+			wantNames: nil,
+		},
+		{
+			name:      "single named placeholder",
+			query:     "SELECT * FROM t WHERE a=:foo",
+			wantQuery: "SELECT * FROM t WHERE a=?",
+			wantNames: map[string]int{"foo": 0},
+		},
+		{
+			name:      "mixing positional and named placeholders orders by position",
+			query:     "SELECT ? , :b FROM t WHERE a=?",
+			wantQuery: "SELECT ? , ? FROM t WHERE a=?",
+			wantNames: map[string]int{"b": 1},
+		},
+		{
+			name:      "bare @name user variable reference is left untouched",
+			query:     "SELECT @rownum := @rownum + 1",
+			wantQuery: "SELECT @rownum := @rownum + 1",
+			wantNames: nil,
+		},
+		{
+			name:      "bare @@ system variable reference is left untouched",
+			query:     "SELECT @@version",
+			wantQuery: "SELECT @@version",
+			wantNames: nil,
+		},
+		{
+			name:      "qualified @@ system variable reference is left untouched",
+			query:     "SELECT * FROM t WHERE a=@@global.sort_buffer_size",
+			wantQuery: "SELECT * FROM t WHERE a=@@global.sort_buffer_size",
+			wantNames: nil,
+		},
+		{
+			name:      "CREATE USER host suffix is left untouched",
+			query:     "CREATE USER bob@localhost IDENTIFIED BY 'pw'",
+			wantQuery: "CREATE USER bob@localhost IDENTIFIED BY 'pw'",
+			wantNames: nil,
+		},
+		{
+			name:      "named placeholder inside a quoted string is not rewritten",
+			query:     `SELECT ':notaparam' FROM t WHERE a=:foo`,
+			wantQuery: "SELECT ':notaparam' FROM t WHERE a=?",
+			wantNames: map[string]int{"foo": 0},
+		},
+		{
+			name:      "backslash-escaped quote does not end the string early",
+			query:     `SELECT 'it\'s :notaparam' FROM t WHERE a=:foo`,
+			wantQuery: `SELECT 'it\'s :notaparam' FROM t WHERE a=?`,
+			wantNames: map[string]int{"foo": 0},
+		},
+		{
+			name:      "backslash escapes are not special inside backtick identifiers",
+			query:     "SELECT `a\\` FROM t WHERE b=:foo",
+			wantQuery: "SELECT `a\\` FROM t WHERE b=?",
+			wantNames: map[string]int{"foo": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotNames, err := rewriteNamedParams(tt.query)
+			if err != nil {
+				t.Fatalf("rewriteNamedParams(%q) returned unexpected error: %v", tt.query, err)
+			}
+			if gotQuery != tt.wantQuery {
+				t.Errorf("query = %q, want %q", gotQuery, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(gotNames, tt.wantNames) {
+				t.Errorf("names = %v, want %v", gotNames, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestRewriteNamedParamsRejectsRepeatedName(t *testing.T) {
+	_, _, err := rewriteNamedParams("SELECT * FROM t WHERE start_date <= :d AND end_date >= :d")
+	if err == nil {
+		t.Fatal("expected an error for a named parameter used more than once")
+	}
+}
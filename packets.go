@@ -0,0 +1,67 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import "encoding/binary"
+
+// defaultCollationID is utf8_general_ci, the collation this driver
+// advertises in the handshake response when none is otherwise configured.
+const defaultCollationID = 33
+
+// writePacket frames data as a single MySQL protocol packet (3-byte
+// little-endian length header + 1-byte sequence id) and writes it to
+// mc.netConn, advancing mc.sequence.
+func (mc *mysqlConn) writePacket(data []byte) error {
+	header := []byte{
+		byte(len(data)),
+		byte(len(data) >> 8),
+		byte(len(data) >> 16),
+		mc.sequence,
+	}
+	mc.sequence++
+	if _, err := mc.netConn.Write(header); err != nil {
+		return err
+	}
+	if _, err := mc.netConn.Write(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeAuthPacket sends the handshake response packet: the capability flags
+// this driver advertises (see clientCapabilityFlags, which turns on
+// CLIENT_MULTI_STATEMENTS/CLIENT_MULTI_RESULTS when cfg.MultiStatements or
+// cfg.MultiResults is set), followed by the username and the authData
+// already computed by the negotiated auth plugin.
+func (mc *mysqlConn) writeAuthPacket(authPluginName string, authData []byte) error {
+	mc.flags = clientCapabilityFlags(mc.cfg)
+	if len(mc.cfg.DBName) > 0 {
+		mc.flags |= clientConnectWithDB
+	}
+
+	data := make([]byte, 4+4+1+23, 64)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(mc.flags))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(maxPacketSize))
+	data[8] = defaultCollationID
+	// bytes 9:32 are reserved and already zero.
+
+	data = append(data, mc.cfg.User...)
+	data = append(data, 0)
+
+	data = append(data, byte(len(authData)))
+	data = append(data, authData...)
+
+	if mc.flags&clientConnectWithDB != 0 {
+		data = append(data, mc.cfg.DBName...)
+		data = append(data, 0)
+	}
+
+	data = append(data, authPluginName...)
+	data = append(data, 0)
+
+	return mc.writePacket(data)
+}
@@ -0,0 +1,130 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"io"
+)
+
+type resultSet struct {
+	columns []mysqlField
+	done    bool
+}
+
+type mysqlRows struct {
+	mc *mysqlConn
+	rs resultSet
+}
+
+type binaryRows struct {
+	mysqlRows
+}
+
+type textRows struct {
+	mysqlRows
+}
+
+func (rows *mysqlRows) Columns() []string {
+	columns := make([]string, len(rows.rs.columns))
+	for i := range columns {
+		columns[i] = rows.rs.columns[i].name
+	}
+	return columns
+}
+
+func (rows *mysqlRows) Close() error {
+	mc := rows.mc
+	if mc == nil {
+		return nil
+	}
+	if mc.netConn == nil {
+		return ErrInvalidConn
+	}
+
+	// Remove unread rows and any subsequent pending result sets, so the
+	// connection is clean for the next query.
+	err := mc.readUntilEOF()
+	for err == nil && rows.HasNextResultSet() {
+		if err = rows.nextResultSetErr(); err != nil {
+			break
+		}
+		err = mc.readUntilEOF()
+	}
+	rows.mc = nil
+	return err
+}
+
+// HasNextResultSet implements driver.RowsNextResultSet.
+func (rows *mysqlRows) HasNextResultSet() (result bool) {
+	if rows.mc == nil {
+		return false
+	}
+	return rows.mc.status&statusMoreResultsExists != 0
+}
+
+// nextResultSetErr advances rows.rs to the next result set, consuming the
+// remainder of the current one if it hasn't been read yet.
+func (rows *mysqlRows) nextResultSetErr() error {
+	if rows.mc == nil {
+		return io.EOF
+	}
+	mc := rows.mc
+	if !rows.rs.done {
+		if err := mc.readUntilEOF(); err != nil {
+			return err
+		}
+		rows.rs.done = true
+	}
+
+	if !rows.HasNextResultSet() {
+		return io.EOF
+	}
+	rows.rs = resultSet{}
+	_, err := mc.readResultSetHeaderPacket()
+	return err
+}
+
+// NextResultSet implements driver.RowsNextResultSet.
+func (rows *binaryRows) NextResultSet() error {
+	if err := rows.nextResultSetErr(); err != nil {
+		return err
+	}
+	columns, err := rows.mc.readColumns()
+	rows.rs.columns = columns
+	return err
+}
+
+func (rows *binaryRows) Next(dest []driver.Value) error {
+	if mc := rows.mc; mc != nil {
+		if mc.closed.Load() {
+			return ErrInvalidConn
+		}
+		return mc.readBinaryRow(rows.rs.columns, dest)
+	}
+	return io.EOF
+}
+
+// NextResultSet implements driver.RowsNextResultSet.
+func (rows *textRows) NextResultSet() error {
+	if err := rows.nextResultSetErr(); err != nil {
+		return err
+	}
+	columns, err := rows.mc.readColumns()
+	rows.rs.columns = columns
+	return err
+}
+
+func (rows *textRows) Next(dest []driver.Value) error {
+	if mc := rows.mc; mc != nil {
+		if mc.closed.Load() {
+			return ErrInvalidConn
+		}
+		return mc.readTextRow(rows.rs.columns, dest)
+	}
+	return io.EOF
+}
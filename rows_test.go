@@ -0,0 +1,34 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import "testing"
+
+func TestRowsHasNextResultSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		mc     *mysqlConn
+		status statusFlag
+		want   bool
+	}{
+		{"no connection", nil, 0, false},
+		{"no more results", &mysqlConn{}, 0, false},
+		{"more results exist", &mysqlConn{}, statusMoreResultsExists, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := &mysqlRows{mc: tt.mc}
+			if rows.mc != nil {
+				rows.mc.status = tt.status
+			}
+			if got := rows.HasNextResultSet(); got != tt.want {
+				t.Errorf("HasNextResultSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
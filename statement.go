@@ -0,0 +1,120 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+type mysqlStmt struct {
+	mc         *mysqlConn
+	id         uint32
+	paramCount int
+
+	// paramNames maps each :name placeholder captured by rewriteNamedParams
+	// at Prepare time to the positional index it was rewritten to. Nil for
+	// statements that only use "?" placeholders.
+	paramNames map[string]int
+}
+
+func (stmt *mysqlStmt) Close() error {
+	if stmt.mc == nil || stmt.mc.closed.Load() {
+		// driver.Stmt.Close can be called more than once, thus this function
+		// has to be idempotent.
+		return nil
+	}
+
+	err := stmt.mc.writeCommandPacketUint32(comStmtClose, stmt.id)
+	stmt.mc = nil
+	return err
+}
+
+func (stmt *mysqlStmt) NumInput() int {
+	return stmt.paramCount
+}
+
+func (stmt *mysqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return stmt.mc.execStmt(stmt, args)
+}
+
+func (stmt *mysqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return stmt.mc.queryStmt(stmt, args)
+}
+
+// namedArgsToValue builds positional args from named, honoring each value's
+// Ordinal rather than its position in the slice: CheckNamedValue rewrites
+// Ordinal for :name placeholders, so by the time database/sql calls
+// ExecContext/QueryContext it may no longer match slice order.
+func namedArgsToValue(named []driver.NamedValue) []driver.Value {
+	args := make([]driver.Value, len(named))
+	for _, nv := range named {
+		args[nv.Ordinal-1] = nv.Value
+	}
+	return args
+}
+
+// ExecContext implements driver.StmtExecContext.
+func (stmt *mysqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := stmt.mc.watchCancel(ctx); err != nil {
+		return nil, err
+	}
+	defer stmt.mc.finish()
+
+	res, err := stmt.mc.execStmt(stmt, namedArgsToValue(args))
+	if err != nil {
+		return nil, stmt.mc.markBadConn(err)
+	}
+	return res, nil
+}
+
+// QueryContext implements driver.StmtQueryContext.
+func (stmt *mysqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := stmt.mc.watchCancel(ctx); err != nil {
+		return nil, err
+	}
+	defer stmt.mc.finish()
+
+	rows, err := stmt.mc.queryStmt(stmt, namedArgsToValue(args))
+	if err != nil {
+		return nil, stmt.mc.markBadConn(err)
+	}
+	return rows, nil
+}
+
+// Prepare implements driver.Conn.
+//
+// :name placeholders are rewritten to positional "?" parameters (see
+// rewriteNamedParams) so callers can pass sql.Named("name", v) values;
+// mysqlStmt.CheckNamedValue resolves each one back to its position using the
+// ordered map captured here.
+func (mc *mysqlConn) Prepare(query string) (driver.Stmt, error) {
+	if mc.closed.Load() {
+		return nil, ErrInvalidConn
+	}
+
+	rewritten, paramNames, err := rewriteNamedParams(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mc.writeCommandPacketStr(comStmtPrepare, rewritten); err != nil {
+		return nil, mc.markBadConn(err)
+	}
+
+	stmt := &mysqlStmt{
+		mc:         mc,
+		paramNames: paramNames,
+	}
+
+	id, paramCount, _, err := mc.readPrepareResultPacket()
+	if err == nil {
+		stmt.id = id
+		stmt.paramCount = paramCount
+	}
+	return stmt, err
+}
@@ -0,0 +1,54 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// isolationLevelSQL maps a database/sql isolation level to the string MySQL
+// expects after "SET TRANSACTION ISOLATION LEVEL ". MySQL has no equivalent
+// of sql.LevelSnapshot (InnoDB's closest match, REPEATABLE READ, has
+// different visibility semantics), so that and any other level it can't
+// express are reported back as errors rather than silently downgraded.
+func isolationLevelSQL(level sql.IsolationLevel) (string, error) {
+	switch level {
+	case sql.LevelReadUncommitted:
+		return "READ UNCOMMITTED", nil
+	case sql.LevelReadCommitted:
+		return "READ COMMITTED", nil
+	case sql.LevelRepeatableRead:
+		return "REPEATABLE READ", nil
+	case sql.LevelSerializable:
+		return "SERIALIZABLE", nil
+	default:
+		return "", fmt.Errorf("mysql: unsupported isolation level: %v", level)
+	}
+}
+
+type mysqlTx struct {
+	mc *mysqlConn
+}
+
+func (tx *mysqlTx) Commit() (err error) {
+	if tx.mc == nil || tx.mc.closed.Load() {
+		return ErrInvalidConn
+	}
+	_, err = tx.mc.exec("COMMIT")
+	tx.mc = nil
+	return
+}
+
+func (tx *mysqlTx) Rollback() (err error) {
+	if tx.mc == nil || tx.mc.closed.Load() {
+		return ErrInvalidConn
+	}
+	_, err = tx.mc.exec("ROLLBACK")
+	tx.mc = nil
+	return
+}
@@ -0,0 +1,45 @@
+// Copyright 2012 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestIsolationLevelSQL(t *testing.T) {
+	tests := []struct {
+		level   sql.IsolationLevel
+		want    string
+		wantErr bool
+	}{
+		{sql.LevelReadUncommitted, "READ UNCOMMITTED", false},
+		{sql.LevelReadCommitted, "READ COMMITTED", false},
+		{sql.LevelRepeatableRead, "REPEATABLE READ", false},
+		{sql.LevelSerializable, "SERIALIZABLE", false},
+		{sql.LevelSnapshot, "", true},
+		{sql.LevelLinearizable, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			got, err := isolationLevelSQL(tt.level)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("isolationLevelSQL(%v) = %q, nil; want an error", tt.level, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("isolationLevelSQL(%v) returned unexpected error: %v", tt.level, err)
+			}
+			if got != tt.want {
+				t.Errorf("isolationLevelSQL(%v) = %q, want %q", tt.level, got, tt.want)
+			}
+		})
+	}
+}